@@ -0,0 +1,111 @@
+package paypal
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// authRefreshMargin is how long before an access token's expiry Client re-authenticates, so a
+// token doesn't expire in the middle of a request.
+const authRefreshMargin = 60 * time.Second
+
+// Client wraps a Config and transparently caches and refreshes the PayPal access token, so
+// callers don't need to call Config.Auth themselves before every request. A Client is safe for
+// concurrent use by multiple goroutines.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+
+	mutex     sync.Mutex
+	result    *AuthResult
+	expiresAt time.Time
+}
+
+// ClientOption configures a Client constructed by NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient overrides the *http.Client a Client uses for PayPal API requests, e.g. for
+// custom timeouts, a proxy, or request tracing. The default is &http.Client{Timeout: 10 *
+// time.Second}.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// NewClient creates a Client that authenticates against PayPal using config.
+func NewClient(config *Config, opts ...ClientOption) *Client {
+	client := &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	// Config.Auth is called internally by client.auth, so it must use the same *http.Client as
+	// every other Client method.
+	config.HTTPClient = client.httpClient
+	return client
+}
+
+// auth returns a cached access token, re-authenticating with PayPal if there is none yet or the
+// cached one is about to expire.
+func (client *Client) auth() (*AuthResult, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.result != nil && time.Now().Before(client.expiresAt) {
+		return client.result, nil
+	}
+
+	result, err := client.config.Auth()
+	if err != nil {
+		return nil, err
+	}
+
+	client.result = result
+	client.expiresAt = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - authRefreshMargin)
+	return result, nil
+}
+
+// requestOptions holds the per-call options accepted by mutating Client methods.
+type requestOptions struct {
+	requestID string
+}
+
+// RequestOption configures a single mutating Client call, see WithRequestID.
+type RequestOption func(*requestOptions)
+
+// WithRequestID sets the PayPal-Request-Id header so that retrying the same call after a network
+// failure is deduplicated by PayPal instead of creating a second order, capture or refund. If not
+// given, a random request ID is generated for the call.
+func WithRequestID(id string) RequestOption {
+	return func(o *requestOptions) {
+		o.requestID = id
+	}
+}
+
+func buildRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.requestID == "" {
+		o.requestID = generateRequestID()
+	}
+	return o
+}
+
+// generateRequestID returns a random UUIDv4 for use as a PayPal-Request-Id header.
+func generateRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,88 @@
+package paypal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrorDetail is one entry of an Error's Details, describing a specific field or issue that
+// caused the request to be rejected.
+type ErrorDetail struct {
+	Issue       string `json:"issue"`
+	Description string `json:"description"`
+	Field       string `json:"field,omitempty"`
+	Location    string `json:"location,omitempty"`
+}
+
+// Error is returned by every PayPal API call whenever PayPal responds with a non-2xx status.
+// Name and Details mirror PayPal's error catalogue, see
+// https://developer.paypal.com/api/rest/responses/, and DebugID should be included in support
+// tickets. Name, Message and Details are empty if the response body wasn't in PayPal's usual
+// error shape, e.g. for the OAuth token endpoint.
+type Error struct {
+	StatusCode int           `json:"-"`
+	Name       string        `json:"name"`
+	Message    string        `json:"message"`
+	DebugID    string        `json:"debug_id"`
+	Details    []ErrorDetail `json:"details,omitempty"`
+	RetryAfter time.Duration `json:"-"` // parsed from the Retry-After header on 429 and 5xx responses, zero if absent
+	RawBody    []byte        `json:"-"`
+}
+
+func (err *Error) Error() string {
+	if err.Name != "" {
+		return fmt.Sprintf("paypal: %s: %s (debug_id %s)", err.Name, err.Message, err.DebugID)
+	}
+	return fmt.Sprintf("paypal: http %s: %s", http.StatusText(err.StatusCode), err.RawBody)
+}
+
+// Retryable reports whether the request that produced err is worth retrying, i.e. PayPal
+// responded with 429 Too Many Requests or a 5xx server error.
+func (err *Error) Retryable() bool {
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= 500
+}
+
+// parseError builds an Error from a non-2xx PayPal API response.
+func parseError(resp *http.Response, body []byte) *Error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		RawBody:    body,
+	}
+	json.Unmarshal(body, apiErr) // best effort; Name/Message/DebugID/Details stay empty otherwise
+
+	if apiErr.Retryable() {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return apiErr
+}
+
+// WithRetry calls fn, retrying up to maxRetries times if it fails with a *Error for which
+// Retryable returns true. Between retries it waits for the duration indicated by the error's
+// RetryAfter, falling back to exponential backoff starting at 1 second if PayPal didn't send one.
+func WithRetry(maxRetries int, fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		apiErr, ok := err.(*Error)
+		if !ok || !apiErr.Retryable() || attempt == maxRetries {
+			return err
+		}
+
+		wait := apiErr.RetryAfter
+		if wait == 0 {
+			wait = time.Duration(1<<attempt) * time.Second
+		}
+		time.Sleep(wait)
+	}
+	return err
+}
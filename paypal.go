@@ -13,10 +13,16 @@ import (
 )
 
 type Config struct {
-	OAuthAPI string `json:"oauth-api"`
-	OrderAPI string `json:"order-api"`
-	ClientID string `json:"client-id"`
-	Secret   string `json:"secret"`
+	OAuthAPI         string `json:"oauth-api"`
+	OrderAPI         string `json:"order-api"`
+	PaymentsAPI      string `json:"payments-api"`      // base URL for the /v2/payments/* endpoints, e.g. refunds, captures and authorizations
+	NotificationsAPI string `json:"notifications-api"` // base URL for the /v1/notifications/* endpoints, e.g. webhook signature verification
+	ClientID         string `json:"client-id"`
+	Secret           string `json:"secret"`
+
+	// HTTPClient is used for the Auth request if set, otherwise a client with a 10 second
+	// timeout is created. It is not read from or written to the json config file.
+	HTTPClient *http.Client `json:"-"`
 }
 
 // Load unmarshals a json config file into a Config.
@@ -41,6 +47,12 @@ func Load(jsonPath string) (*Config, error) {
 	if config.OrderAPI == "" {
 		return nil, errors.New("missing order-api in paypal config file")
 	}
+	if config.PaymentsAPI == "" {
+		return nil, errors.New("missing payments-api in paypal config file")
+	}
+	if config.NotificationsAPI == "" {
+		return nil, errors.New("missing notifications-api in paypal config file")
+	}
 	if config.ClientID == "" {
 		return nil, errors.New("missing client-id in paypal config file")
 	}
@@ -72,23 +84,74 @@ type AuthResult struct {
 	Nonce       string `json:"nonce"`
 }
 
+// Order intents, see https://developer.paypal.com/docs/api/orders/v2/#orders_create
+const (
+	IntentCapture   = "CAPTURE"
+	IntentAuthorize = "AUTHORIZE"
+)
+
 type OrderRequest struct {
 	Intent             string             `json:"intent"`
 	PurchaseUnits      []PurchaseUnit     `json:"purchase_units"`
 	ApplicationContext ApplicationContext `json:"application_context"`
 }
 
+// CreateOrderRequest describes the order that CreateOrder sets up with PayPal.
+type CreateOrderRequest struct {
+	Intent      string // IntentCapture or IntentAuthorize, defaults to IntentCapture
+	Amount      Amount // total amount; set Amount.Breakdown and Items for an itemised cart
+	Items       []Item // optional, must sum up to Amount.Breakdown.ItemTotal when set
+	Description string
+	CustomID    string
+	InvoiceID   string
+}
+
 // See https://developer.paypal.com/docs/api/orders/v2/#definition-purchase_unit
 type PurchaseUnit struct {
 	Amount      Amount `json:"amount"`
+	Items       []Item `json:"items,omitempty"`
 	Description string `json:"description"` // "[ 1 .. 127 ] characters: The purchase description."
 	CustomID    string `json:"custom_id"`   // "[ 1 .. 127 ] characters: The API caller-provided external ID. Used to reconcile API caller-initiated transactions with PayPal transactions. Appears in transaction and settlement reports."
 	InvoiceID   string `json:"invoice_id"`  // "[ 1 .. 127 ] characters: The API caller-provided external invoice ID for this order. Appears in both the payer's transaction history and the emails that the payer receives."
 }
 
+// Money is a fixed-point decimal amount in a given currency. PayPal requires Value as a decimal
+// string (e.g. "12.34"), not a float, to avoid rounding errors.
+type Money struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+// Amount is the total amount of a PurchaseUnit. Breakdown is optional and, when set, must add up
+// to Value; it is required for itemised carts (see Items on PurchaseUnit) and for compliance in
+// some jurisdictions.
 type Amount struct {
-	CurrencyCode string  `json:"currency_code"`
-	Value        float64 `json:"value"`
+	CurrencyCode string           `json:"currency_code"`
+	Value        string           `json:"value"`
+	Breakdown    *AmountBreakdown `json:"breakdown,omitempty"`
+}
+
+// AmountBreakdown itemises an Amount. See
+// https://developer.paypal.com/docs/api/orders/v2/#definition-amount_breakdown
+type AmountBreakdown struct {
+	ItemTotal        *Money `json:"item_total,omitempty"`
+	Shipping         *Money `json:"shipping,omitempty"`
+	TaxTotal         *Money `json:"tax_total,omitempty"`
+	Handling         *Money `json:"handling,omitempty"`
+	Insurance        *Money `json:"insurance,omitempty"`
+	ShippingDiscount *Money `json:"shipping_discount,omitempty"`
+	Discount         *Money `json:"discount,omitempty"`
+}
+
+// Item is a single line item of a PurchaseUnit's Items.
+// See https://developer.paypal.com/docs/api/orders/v2/#definition-item
+type Item struct {
+	Name       string `json:"name"`
+	Quantity   string `json:"quantity"`
+	UnitAmount Money  `json:"unit_amount"`
+	Tax        *Money `json:"tax,omitempty"`
+	Category   string `json:"category,omitempty"` // e.g. "PHYSICAL_GOODS", "DIGITAL_GOODS"
+	SKU        string `json:"sku,omitempty"`
 }
 
 type ApplicationContext struct {
@@ -109,7 +172,8 @@ type SuccessResponse struct {
 	OrderID string `json:"id"`
 }
 
-// Auth gets an access token from the PayPal API.
+// Auth gets an access token from the PayPal API. It is a low-level primitive; most callers
+// should use Client instead, which caches and refreshes the token automatically.
 func (config *Config) Auth() (*AuthResult, error) {
 
 	req, err := http.NewRequest(
@@ -124,9 +188,12 @@ func (config *Config) Auth() (*AuthResult, error) {
 	req.Header.Add("Accept", "application/json")
 	req.SetBasicAuth(config.ClientID, config.Secret)
 
-	resp, err := (&http.Client{
-		Timeout: 10 * time.Second,
-	}).Do(req)
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +204,7 @@ func (config *Config) Auth() (*AuthResult, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error getting auth: %s: %s", resp.Status, body)
+		return nil, parseError(resp, body)
 	}
 
 	var authResult = &AuthResult{}
@@ -145,19 +212,29 @@ func (config *Config) Auth() (*AuthResult, error) {
 }
 
 // CreateOrder calls PayPal to set up a transaction.
-func (config *Config) CreateOrder(auth *AuthResult, description, customID, invoiceID string, euroCents int) (*GenerateOrderResponse, error) {
+func (client *Client) CreateOrder(create CreateOrderRequest, opts ...RequestOption) (*GenerateOrderResponse, error) {
+
+	auth, err := client.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	requestOptions := buildRequestOptions(opts)
+
+	intent := create.Intent
+	if intent == "" {
+		intent = IntentCapture
+	}
 
 	orderRequest := &OrderRequest{
-		Intent: "CAPTURE",
+		Intent: intent,
 		PurchaseUnits: []PurchaseUnit{
 			PurchaseUnit{
-				Amount: Amount{
-					CurrencyCode: "EUR",
-					Value:        float64(euroCents) / 100.0,
-				},
-				Description: description,
-				CustomID:    customID,
-				InvoiceID:   invoiceID,
+				Amount:      create.Amount,
+				Items:       create.Items,
+				Description: create.Description,
+				CustomID:    create.CustomID,
+				InvoiceID:   create.InvoiceID,
 			},
 		},
 		ApplicationContext: ApplicationContext{
@@ -172,7 +249,7 @@ func (config *Config) CreateOrder(auth *AuthResult, description, customID, invoi
 
 	req, err := http.NewRequest(
 		http.MethodPost,
-		config.OrderAPI,
+		client.config.OrderAPI,
 		bytes.NewBuffer(orJson),
 	)
 	if err != nil {
@@ -182,10 +259,9 @@ func (config *Config) CreateOrder(auth *AuthResult, description, customID, invoi
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
 	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("PayPal-Request-Id", requestOptions.requestID)
 
-	resp, err := (&http.Client{
-		Timeout: 10 * time.Second,
-	}).Do(req)
+	resp, err := client.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -196,7 +272,7 @@ func (config *Config) CreateOrder(auth *AuthResult, description, customID, invoi
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("error doing order: %s: %s", resp.Status, body)
+		return nil, parseError(resp, body)
 	}
 
 	generateOrderResponse := &GenerateOrderResponse{}
@@ -280,11 +356,18 @@ type CaptureResponse struct {
 }
 
 // Capture calls PayPal to capture the order.
-func (config *Config) Capture(auth *AuthResult, orderID string) (*CaptureResponse, error) {
+func (client *Client) Capture(orderID string, opts ...RequestOption) (*CaptureResponse, error) {
+
+	auth, err := client.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	requestOptions := buildRequestOptions(opts)
 
 	req, err := http.NewRequest(
 		http.MethodPost,
-		ensureTrailingSlash(config.OrderAPI)+orderID+"/capture",
+		ensureTrailingSlash(client.config.OrderAPI)+orderID+"/capture",
 		nil,
 	)
 	if err != nil {
@@ -294,10 +377,9 @@ func (config *Config) Capture(auth *AuthResult, orderID string) (*CaptureRespons
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
 	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("PayPal-Request-Id", requestOptions.requestID)
 
-	resp, err := (&http.Client{
-		Timeout: 10 * time.Second,
-	}).Do(req)
+	resp, err := client.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -308,13 +390,355 @@ func (config *Config) Capture(auth *AuthResult, orderID string) (*CaptureRespons
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("error capturing: %s: %s", resp.Status, body)
+		return nil, parseError(resp, body)
 	}
 
 	captureResponse := &CaptureResponse{}
 	return captureResponse, json.Unmarshal(body, captureResponse)
 }
 
+// GetOrder calls PayPal to look up the current state of an order.
+func (client *Client) GetOrder(orderID string) (*CaptureResponse, error) {
+
+	auth, err := client.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodGet,
+		ensureTrailingSlash(client.config.OrderAPI)+orderID,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp, body)
+	}
+
+	orderResponse := &CaptureResponse{}
+	return orderResponse, json.Unmarshal(body, orderResponse)
+}
+
+type AuthorizeOrderResponse struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	PurchaseUnits []struct {
+		ReferenceID string `json:"reference_id"`
+		Payments    struct {
+			Authorizations []AuthorizationResponse `json:"authorizations"`
+		} `json:"payments"`
+	} `json:"purchase_units"`
+	Links []struct {
+		Href   string `json:"href"`
+		Rel    string `json:"rel"`
+		Method string `json:"method"`
+	} `json:"links"`
+}
+
+// AuthorizeOrder calls PayPal to authorize an order that was created with IntentAuthorize.
+func (client *Client) AuthorizeOrder(orderID string, opts ...RequestOption) (*AuthorizeOrderResponse, error) {
+
+	auth, err := client.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	requestOptions := buildRequestOptions(opts)
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		ensureTrailingSlash(client.config.OrderAPI)+orderID+"/authorize",
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("PayPal-Request-Id", requestOptions.requestID)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseError(resp, body)
+	}
+
+	authorizeOrderResponse := &AuthorizeOrderResponse{}
+	return authorizeOrderResponse, json.Unmarshal(body, authorizeOrderResponse)
+}
+
+type AuthorizationResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount struct {
+		CurrencyCode string `json:"currency_code"`
+		Value        string `json:"value"`
+	} `json:"amount"`
+	ExpirationTime time.Time `json:"expiration_time"`
+	Links          []struct {
+		Href   string `json:"href"`
+		Rel    string `json:"rel"`
+		Method string `json:"method"`
+	} `json:"links"`
+}
+
+// VoidAuthorization calls PayPal to void an authorization so it can no longer be captured.
+func (client *Client) VoidAuthorization(authorizationID string, opts ...RequestOption) error {
+
+	auth, err := client.auth()
+	if err != nil {
+		return err
+	}
+
+	requestOptions := buildRequestOptions(opts)
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		ensureTrailingSlash(client.config.PaymentsAPI)+"authorizations/"+authorizationID+"/void",
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+	req.Header.Add("PayPal-Request-Id", requestOptions.requestID)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseError(resp, body)
+	}
+
+	return nil
+}
+
+// ReauthorizeAuthorization calls PayPal to extend the honor period of an authorization by
+// another 3 days. amount may be nil to reauthorize the original amount.
+func (client *Client) ReauthorizeAuthorization(authorizationID string, amount *Amount, opts ...RequestOption) (*AuthorizationResponse, error) {
+
+	auth, err := client.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	requestOptions := buildRequestOptions(opts)
+
+	reauthorizeRequest := &struct {
+		Amount *Amount `json:"amount,omitempty"`
+	}{Amount: amount}
+
+	reJson, err := json.Marshal(reauthorizeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		ensureTrailingSlash(client.config.PaymentsAPI)+"authorizations/"+authorizationID+"/reauthorize",
+		bytes.NewBuffer(reJson),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("PayPal-Request-Id", requestOptions.requestID)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseError(resp, body)
+	}
+
+	authorizationResponse := &AuthorizationResponse{}
+	return authorizationResponse, json.Unmarshal(body, authorizationResponse)
+}
+
+type CaptureDetailResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount struct {
+		CurrencyCode string `json:"currency_code"`
+		Value        string `json:"value"`
+	} `json:"amount"`
+	FinalCapture     bool `json:"final_capture"`
+	SellerProtection struct {
+		Status            string   `json:"status"`
+		DisputeCategories []string `json:"dispute_categories"`
+	} `json:"seller_protection"`
+	SellerReceivableBreakdown struct {
+		GrossAmount struct {
+			CurrencyCode string `json:"currency_code"`
+			Value        string `json:"value"`
+		} `json:"gross_amount"`
+		PaypalFee struct {
+			CurrencyCode string `json:"currency_code"`
+			Value        string `json:"value"`
+		} `json:"paypal_fee"`
+		NetAmount struct {
+			CurrencyCode string `json:"currency_code"`
+			Value        string `json:"value"`
+		} `json:"net_amount"`
+	} `json:"seller_receivable_breakdown"`
+	Links []struct {
+		Href   string `json:"href"`
+		Rel    string `json:"rel"`
+		Method string `json:"method"`
+	} `json:"links"`
+	CreateTime time.Time `json:"create_time"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// GetCapture calls PayPal to look up the current state of a capture.
+func (client *Client) GetCapture(captureID string) (*CaptureDetailResponse, error) {
+
+	auth, err := client.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodGet,
+		ensureTrailingSlash(client.config.PaymentsAPI)+"captures/"+captureID,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp, body)
+	}
+
+	captureDetailResponse := &CaptureDetailResponse{}
+	return captureDetailResponse, json.Unmarshal(body, captureDetailResponse)
+}
+
+type RefundResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount struct {
+		CurrencyCode string `json:"currency_code"`
+		Value        string `json:"value"`
+	} `json:"amount"`
+	Links []struct {
+		Href   string `json:"href"`
+		Rel    string `json:"rel"`
+		Method string `json:"method"`
+	} `json:"links"`
+}
+
+// RefundCapture calls PayPal to refund a previously captured payment. If amount is nil, the
+// capture is refunded in full.
+func (client *Client) RefundCapture(captureID string, amount *Amount, opts ...RequestOption) (*RefundResponse, error) {
+
+	auth, err := client.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	requestOptions := buildRequestOptions(opts)
+
+	refundRequest := &struct {
+		Amount *Amount `json:"amount,omitempty"`
+	}{Amount: amount}
+
+	refJson, err := json.Marshal(refundRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		ensureTrailingSlash(client.config.PaymentsAPI)+"captures/"+captureID+"/refund",
+		bytes.NewBuffer(refJson),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("PayPal-Request-Id", requestOptions.requestID)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseError(resp, body)
+	}
+
+	refundResponse := &RefundResponse{}
+	return refundResponse, json.Unmarshal(body, refundResponse)
+}
+
 func ensureTrailingSlash(s string) string {
 	if strings.HasSuffix(s, "/") {
 		return s
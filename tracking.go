@@ -0,0 +1,157 @@
+package paypal
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// TrackingItem identifies a purchase unit item covered by a TrackingInfo entry.
+type TrackingItem struct {
+	Name     string `json:"name"`
+	Quantity string `json:"quantity"`
+}
+
+// TrackingInfo is the shipment information submitted to PayPal for a captured order via
+// AddTracking. See https://developer.paypal.com/docs/tracking/integrate/ for the supported
+// carrier values.
+type TrackingInfo struct {
+	CaptureID      string         `json:"capture_id"`
+	TrackingNumber string         `json:"tracking_number"`
+	Carrier        string         `json:"carrier"`
+	NotifyPayer    bool           `json:"notify_payer"`
+	Items          []TrackingItem `json:"items,omitempty"`
+}
+
+// AddTracking calls PayPal to add shipment tracking information to a captured order. PayPal uses
+// this information for seller protection and to notify the payer, if requested.
+func (client *Client) AddTracking(orderID string, tracking TrackingInfo, opts ...RequestOption) error {
+
+	auth, err := client.auth()
+	if err != nil {
+		return err
+	}
+
+	requestOptions := buildRequestOptions(opts)
+
+	trJson, err := json.Marshal(tracking)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		ensureTrailingSlash(client.config.OrderAPI)+orderID+"/track",
+		bytes.NewBuffer(trJson),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("PayPal-Request-Id", requestOptions.requestID)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return parseError(resp, body)
+	}
+
+	return nil
+}
+
+// UpdateTracking calls PayPal to replace the tracking information previously added under
+// trackerID for orderID.
+func (client *Client) UpdateTracking(orderID, trackerID string, tracking TrackingInfo, opts ...RequestOption) error {
+
+	auth, err := client.auth()
+	if err != nil {
+		return err
+	}
+
+	requestOptions := buildRequestOptions(opts)
+
+	trJson, err := json.Marshal(tracking)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPatch,
+		ensureTrailingSlash(client.config.OrderAPI)+orderID+"/trackers/"+trackerID,
+		bytes.NewBuffer(trJson),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("PayPal-Request-Id", requestOptions.requestID)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseError(resp, body)
+	}
+
+	return nil
+}
+
+// DeleteTracking calls PayPal to remove the tracking information previously added under
+// trackerID for orderID.
+func (client *Client) DeleteTracking(orderID, trackerID string) error {
+
+	auth, err := client.auth()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodDelete,
+		ensureTrailingSlash(client.config.OrderAPI)+orderID+"/trackers/"+trackerID,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseError(resp, body)
+	}
+
+	return nil
+}
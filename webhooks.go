@@ -0,0 +1,156 @@
+package paypal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookEvent is a decoded PayPal webhook notification.
+// See https://developer.paypal.com/api/rest/webhooks/event-names/ for the possible EventType values.
+type WebhookEvent struct {
+	ID           string          `json:"id"`
+	EventType    string          `json:"event_type"`
+	ResourceType string          `json:"resource_type"`
+	Summary      string          `json:"summary"`
+	Resource     json.RawMessage `json:"resource"`
+}
+
+// OrderApprovedResource is the resource of a CHECKOUT.ORDER.APPROVED event.
+type OrderApprovedResource struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// CaptureCompletedResource is the resource of a PAYMENT.CAPTURE.COMPLETED event.
+type CaptureCompletedResource struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount struct {
+		CurrencyCode string `json:"currency_code"`
+		Value        string `json:"value"`
+	} `json:"amount"`
+	CustomID string `json:"custom_id"`
+}
+
+// CaptureRefundedResource is the resource of a PAYMENT.CAPTURE.REFUNDED event.
+type CaptureRefundedResource struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount struct {
+		CurrencyCode string `json:"currency_code"`
+		Value        string `json:"value"`
+	} `json:"amount"`
+}
+
+// DisputeCreatedResource is the resource of a CUSTOMER.DISPUTE.CREATED event.
+type DisputeCreatedResource struct {
+	DisputeID    string `json:"dispute_id"`
+	Reason       string `json:"reason"`
+	Status       string `json:"status"`
+	DisputeState string `json:"dispute_state"`
+}
+
+type verifyWebhookSignatureRequest struct {
+	AuthAlgo         string          `json:"auth_algo"`
+	CertURL          string          `json:"cert_url"`
+	TransmissionID   string          `json:"transmission_id"`
+	TransmissionSig  string          `json:"transmission_sig"`
+	TransmissionTime string          `json:"transmission_time"`
+	WebhookID        string          `json:"webhook_id"`
+	WebhookEvent     json.RawMessage `json:"webhook_event"`
+}
+
+type verifyWebhookSignatureResponse struct {
+	VerificationStatus string `json:"verification_status"`
+}
+
+// VerifyWebhookSignature calls PayPal to verify that rawBody was genuinely sent by PayPal for
+// the webhook identified by webhookID, and returns the decoded event on success. headers must
+// contain the PAYPAL-AUTH-ALGO, PAYPAL-CERT-URL, PAYPAL-TRANSMISSION-ID, PAYPAL-TRANSMISSION-SIG
+// and PAYPAL-TRANSMISSION-TIME headers from the incoming webhook request.
+func (client *Client) VerifyWebhookSignature(headers http.Header, rawBody []byte, webhookID string) (*WebhookEvent, error) {
+
+	auth, err := client.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	verifyRequest := &verifyWebhookSignatureRequest{
+		AuthAlgo:         headers.Get("PAYPAL-AUTH-ALGO"),
+		CertURL:          headers.Get("PAYPAL-CERT-URL"),
+		TransmissionID:   headers.Get("PAYPAL-TRANSMISSION-ID"),
+		TransmissionSig:  headers.Get("PAYPAL-TRANSMISSION-SIG"),
+		TransmissionTime: headers.Get("PAYPAL-TRANSMISSION-TIME"),
+		WebhookID:        webhookID,
+		WebhookEvent:     rawBody,
+	}
+
+	vJson, err := json.Marshal(verifyRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		ensureTrailingSlash(client.config.NotificationsAPI)+"verify-webhook-signature",
+		bytes.NewBuffer(vJson),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+auth.AccessToken)
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp, body)
+	}
+
+	verifyResponse := &verifyWebhookSignatureResponse{}
+	if err := json.Unmarshal(body, verifyResponse); err != nil {
+		return nil, err
+	}
+	if verifyResponse.VerificationStatus != "SUCCESS" {
+		return nil, fmt.Errorf("webhook signature verification failed: %s", verifyResponse.VerificationStatus)
+	}
+
+	event := &WebhookEvent{}
+	return event, json.Unmarshal(rawBody, event)
+}
+
+// Handler returns an http.Handler that verifies incoming PayPal webhook notifications for
+// webhookID and calls handler for each one whose signature is valid. Invalid or unverifiable
+// notifications are rejected with an appropriate HTTP status code and never reach handler.
+func (client *Client) Handler(webhookID string, handler func(*WebhookEvent)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		rawBody, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "error reading body", http.StatusBadRequest)
+			return
+		}
+
+		event, err := client.VerifyWebhookSignature(r.Header, rawBody, webhookID)
+		if err != nil {
+			http.Error(w, "error verifying webhook signature", http.StatusUnauthorized)
+			return
+		}
+
+		handler(event)
+		w.WriteHeader(http.StatusOK)
+	})
+}